@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func ToContext(ctx context.Context, logger *Logger) context.Context {
@@ -14,16 +15,39 @@ func FromContext(ctx context.Context) *Logger {
 	return &Logger{l: zerolog.Ctx(ctx)}
 }
 
-//func loggerFromSpanContext(zl *zerolog.Logger, ctx opentracing.SpanContext) *zerolog.Logger {
-//	spanCtx, ok := ctx.(*jaeger.SpanContext)
-//	if !ok {
-//		return zl
-//	}
-//
-//	logger := zl.With().
-//		Str("trace_id", spanCtx.TraceID().String()).
-//		Str("span_id", spanCtx.SpanID().String()).
-//		Logger()
-//
-//	return &logger
-//}
+// FromContextWithTrace behaves like FromContext, but additionally attaches
+// trace_id, span_id and trace_flags fields pulled from the active span in
+// ctx, if any. It is a no-op with respect to tracing when ctx carries no
+// valid span context.
+func FromContextWithTrace(ctx context.Context) *Logger {
+	l := FromContext(ctx)
+	if kvs := traceKVs(ctx); len(kvs) > 0 {
+		l = l.With(kvs...)
+	}
+	return l
+}
+
+// traceKVs extracts the OpenTelemetry span context from ctx, returning it as
+// a flat kvs slice suitable for Logger.With/Fields. It returns nil when ctx
+// carries no valid span context.
+func traceKVs(ctx context.Context) []interface{} {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.IsValid() {
+		return nil
+	}
+
+	return []interface{}{
+		"trace_id", spanCtx.TraceID().String(),
+		"span_id", spanCtx.SpanID().String(),
+		"trace_flags", spanCtx.TraceFlags().String(),
+	}
+}
+
+// loggerFromContext returns the logger for ctx, honoring WithTraceContext:
+// when enabled, the returned logger carries the active span's trace fields.
+func loggerFromContext(ctx context.Context) *Logger {
+	if traceContextEnabled.Load() {
+		return FromContextWithTrace(ctx)
+	}
+	return FromContext(ctx)
+}
@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func spanContext(traceID, spanID string) trace.SpanContext {
+	tid, _ := trace.TraceIDFromHex(traceID)
+	sid, _ := trace.SpanIDFromHex(spanID)
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    tid,
+		SpanID:     sid,
+		TraceFlags: trace.FlagsSampled,
+	})
+}
+
+func Test_FromContextWithTrace(t *testing.T) {
+	t.Parallel()
+
+	t.Run("attaches trace fields from a valid span context", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		buf := new(bytes.Buffer)
+		logger := New(DebugLevel, testJsonEnable(buf))
+		sc := spanContext("4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7")
+		ctx := trace.ContextWithSpanContext(ToContext(context.Background(), logger), sc)
+
+		// Act
+		FromContextWithTrace(ctx).Info("withTrace")
+
+		// Assert
+		log := newTestLogType(buf.Bytes())
+		assert.Equal(t, "withTrace", log.Message)
+		assert.Contains(t, buf.String(), `"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736"`)
+		assert.Contains(t, buf.String(), `"span_id":"00f067aa0ba902b7"`)
+	})
+
+	t.Run("is a no-op when ctx carries no valid span context", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		buf := new(bytes.Buffer)
+		logger := New(DebugLevel, testJsonEnable(buf))
+		ctx := ToContext(context.Background(), logger)
+
+		// Act
+		FromContextWithTrace(ctx).Info("noTrace")
+
+		// Assert
+		assert.NotContains(t, buf.String(), "trace_id")
+		assert.NotContains(t, buf.String(), "span_id")
+	})
+}
+
+func Test_RecordError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("logs the error and annotates the active span", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		exporter := tracetest.NewInMemoryExporter()
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+		defer func() { _ = tp.Shutdown(context.Background()) }()
+
+		ctx, span := tp.Tracer("logger_test").Start(context.Background(), "op")
+		buf := new(bytes.Buffer)
+		logger := New(DebugLevel, testJsonEnable(buf))
+
+		// Act
+		logger.RecordError(ctx, errors.New("boom"), "failed")
+		span.End()
+
+		// Assert: logged like a normal Error call
+		log := newTestLogType(buf.Bytes())
+		assert.Equal(t, "failed", log.Message)
+		assert.Equal(t, "boom", log.Error)
+
+		// Assert: the active span was annotated with the error and marked errored
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, otelcodes.Error, spans[0].Status.Code)
+		require.Len(t, spans[0].Events, 1)
+		assert.Equal(t, "exception", spans[0].Events[0].Name)
+	})
+
+	t.Run("still logs when ctx carries no active span", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		buf := new(bytes.Buffer)
+		logger := New(DebugLevel, testJsonEnable(buf))
+
+		// Act
+		logger.RecordError(context.Background(), errors.New("boom"), "failed")
+
+		// Assert
+		log := newTestLogType(buf.Bytes())
+		assert.Equal(t, "failed", log.Message)
+		assert.Equal(t, "boom", log.Error)
+	})
+}
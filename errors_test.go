@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/VladimirYalumov/logger/kverrors"
+)
+
+type causesLogLine struct {
+	Error struct {
+		Causes []kverrors.Layer `json:"causes"`
+	} `json:"error"`
+}
+
+func Test_MarshalError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("a kverrors chain is logged as nested causes", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		ctx, buf := getTestData()
+		err := kverrors.Wrap(kverrors.New("dial failed", "host", "db1"), "query failed", "table", "users")
+
+		// Act
+		Error(ctx, err, "operation failed")
+
+		// Assert
+		var line causesLogLine
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+		require.Len(t, line.Error.Causes, 2)
+		assert.Equal(t, "query failed", line.Error.Causes[0].Msg)
+		assert.Equal(t, "users", line.Error.Causes[0].Kvs["table"])
+		assert.Equal(t, "dial failed", line.Error.Causes[1].Msg)
+		assert.Equal(t, "db1", line.Error.Causes[1].Kvs["host"])
+	})
+
+	t.Run("a plain error is left as a flat string", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		ctx, buf := getTestData()
+
+		// Act
+		Error(ctx, errors.New("boom"), "operation failed")
+
+		// Assert
+		log := newTestLogType(buf.Bytes())
+		assert.Equal(t, "boom", log.Error)
+	})
+}
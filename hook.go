@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// Hook is invoked for every event that passes sampling and filtering, after
+// its level and message are known but before it is written to the sink.
+// Hooks are typically used for side effects such as per-level metrics.
+type Hook interface {
+	Run(level Level, msg string, kvs []interface{})
+}
+
+// HookFunc adapts a plain function to a Hook.
+type HookFunc func(level Level, msg string, kvs []interface{})
+
+func (f HookFunc) Run(level Level, msg string, kvs []interface{}) {
+	f(level, msg, kvs)
+}
+
+// Sampler decides whether an event at level should be logged. It is
+// consulted before Hooks run and before the event reaches the sink.
+type Sampler interface {
+	Sample(level Level) bool
+}
+
+// BasicSampler logs one out of every N events and drops the rest. N <= 1
+// samples every event.
+type BasicSampler struct {
+	N uint32
+
+	mu      sync.Mutex
+	counter uint32
+}
+
+func (s *BasicSampler) Sample(Level) bool {
+	if s.N <= 1 {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counter++
+	return s.counter%s.N == 1
+}
+
+// BurstSampler allows the first Burst events within each Period through,
+// then delegates the decision to NextSampler. A nil NextSampler drops every
+// event past the burst for the remainder of the period.
+type BurstSampler struct {
+	Burst       uint32
+	Period      time.Duration
+	NextSampler Sampler
+
+	mu         sync.Mutex
+	windowFrom time.Time
+	count      uint32
+}
+
+func (s *BurstSampler) Sample(level Level) bool {
+	s.mu.Lock()
+	now := time.Now()
+	if s.windowFrom.IsZero() || now.Sub(s.windowFrom) >= s.Period {
+		s.windowFrom = now
+		s.count = 0
+	}
+	s.count++
+	withinBurst := s.count <= s.Burst
+	s.mu.Unlock()
+
+	if withinBurst {
+		return true
+	}
+	if s.NextSampler == nil {
+		return false
+	}
+	return s.NextSampler.Sample(level)
+}
+
+// LevelSampler applies a different Sampler per level. A nil entry samples
+// every event at that level. DebugLevel and anything below it share Debug.
+type LevelSampler struct {
+	Debug Sampler
+	Info  Sampler
+	Warn  Sampler
+	Error Sampler
+}
+
+func (s LevelSampler) Sample(level Level) bool {
+	var sampler Sampler
+	switch {
+	case level <= DebugLevel:
+		sampler = s.Debug
+	case level == InfoLevel:
+		sampler = s.Info
+	case level == WarnLevel:
+		sampler = s.Warn
+	default:
+		sampler = s.Error
+	}
+
+	if sampler == nil {
+		return true
+	}
+	return sampler.Sample(level)
+}
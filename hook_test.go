@@ -0,0 +1,173 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BasicSampler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("N<=1 samples every event", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		s := &BasicSampler{N: 1}
+
+		// Act / Assert
+		for i := 0; i < 5; i++ {
+			assert.True(t, s.Sample(InfoLevel))
+		}
+	})
+
+	t.Run("N=3 samples one out of every three events", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		s := &BasicSampler{N: 3}
+
+		// Act
+		var got []bool
+		for i := 0; i < 6; i++ {
+			got = append(got, s.Sample(InfoLevel))
+		}
+
+		// Assert
+		assert.Equal(t, []bool{true, false, false, true, false, false}, got)
+	})
+}
+
+func Test_BurstSampler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("allows the first Burst events through then falls back to NextSampler", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		s := &BurstSampler{Burst: 2, Period: time.Hour, NextSampler: &BasicSampler{N: 0}}
+
+		// Act / Assert: within burst
+		assert.True(t, s.Sample(InfoLevel))
+		assert.True(t, s.Sample(InfoLevel))
+
+		// Act / Assert: past burst, delegates to NextSampler (N<=1 samples everything)
+		assert.True(t, s.Sample(InfoLevel))
+	})
+
+	t.Run("drops events past the burst with a nil NextSampler", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		s := &BurstSampler{Burst: 1, Period: time.Hour}
+
+		// Act / Assert
+		assert.True(t, s.Sample(InfoLevel))
+		assert.False(t, s.Sample(InfoLevel))
+	})
+
+	t.Run("resets the burst once Period elapses", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		s := &BurstSampler{Burst: 1, Period: time.Millisecond}
+
+		// Act / Assert
+		assert.True(t, s.Sample(InfoLevel))
+		assert.False(t, s.Sample(InfoLevel))
+		time.Sleep(5 * time.Millisecond)
+		assert.True(t, s.Sample(InfoLevel))
+	})
+}
+
+func Test_LevelSampler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("dispatches to the sampler matching the event's level", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		s := LevelSampler{
+			Debug: &BasicSampler{N: 0}, // nil-equivalent: always samples
+			Error: &dropSampler{},
+		}
+
+		// Act / Assert
+		assert.True(t, s.Sample(DebugLevel))
+		assert.False(t, s.Sample(ErrorLevel))
+	})
+
+	t.Run("samples every event at a level with no configured sampler", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		s := LevelSampler{}
+
+		// Act / Assert
+		assert.True(t, s.Sample(InfoLevel))
+		assert.True(t, s.Sample(WarnLevel))
+	})
+}
+
+// dropSampler is a test Sampler that never lets an event through.
+type dropSampler struct{}
+
+func (dropSampler) Sample(Level) bool { return false }
+
+func Test_FilterKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("drops events whose key fails the allow predicate", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		ctx, buf := getTestData()
+		logger := FromContext(ctx).FilterKey("tenant", func(v interface{}) bool {
+			return v == "allowed"
+		})
+		ctx = ToContext(ctx, logger)
+
+		// Act
+		Info(ctx, "visible", "tenant", "allowed")
+		Info(ctx, "hidden", "tenant", "blocked")
+
+		// Assert
+		log := buf.String()
+		assert.Contains(t, log, "visible")
+		assert.NotContains(t, log, "hidden")
+	})
+
+	t.Run("does not affect events that don't carry the filtered key", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		ctx, buf := getTestData()
+		logger := FromContext(ctx).FilterKey("tenant", func(v interface{}) bool {
+			return v == "allowed"
+		})
+		ctx = ToContext(ctx, logger)
+
+		// Act
+		Info(ctx, "untouched")
+
+		// Assert
+		assert.Contains(t, buf.String(), "untouched")
+	})
+
+	t.Run("leaves the original logger unfiltered", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		ctx, buf := getTestData()
+		base := FromContext(ctx)
+		_ = base.FilterKey("tenant", func(v interface{}) bool { return false })
+		ctx = ToContext(ctx, base)
+
+		// Act
+		Info(ctx, "stillVisible", "tenant", "anything")
+
+		// Assert
+		assert.Contains(t, buf.String(), "stillVisible")
+	})
+}
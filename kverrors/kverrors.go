@@ -0,0 +1,147 @@
+// Package kverrors provides structured errors that carry key-value context
+// alongside their message, so a wrap chain can be logged as nested
+// structured fields instead of a single flattened string.
+package kverrors
+
+import (
+	"github.com/pkg/errors"
+)
+
+// kvError is an error carrying a message and key-value context, optionally
+// wrapping a cause. Only the deepest error in a Wrap chain (the one created
+// via New) holds a stack trace.
+type kvError struct {
+	msg   string
+	kvs   []interface{}
+	cause error
+	stack errors.StackTrace
+}
+
+// New returns an error carrying msg and kvs as structured context. It
+// captures a stack trace, via github.com/pkg/errors, that is preserved
+// through any later Wrap calls.
+func New(msg string, kvs ...interface{}) error {
+	return &kvError{msg: msg, kvs: kvs, stack: callers()}
+}
+
+// Wrap annotates err with msg and kvs, keeping err as the cause so the full
+// chain can be walked via Unwrap/Ctx/Causes. It returns nil if err is nil.
+func Wrap(err error, msg string, kvs ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return &kvError{msg: msg, kvs: kvs, cause: err}
+}
+
+func (e *kvError) Error() string {
+	if e.cause == nil {
+		return e.msg
+	}
+	return e.msg + ": " + e.cause.Error()
+}
+
+func (e *kvError) Unwrap() error {
+	return e.cause
+}
+
+// StackTrace satisfies github.com/pkg/errors' stack-tracer interface,
+// delegating to the deepest layer of the chain, so zerolog's
+// pkgerrors.MarshalStack can render it.
+func (e *kvError) StackTrace() errors.StackTrace {
+	if e.stack != nil {
+		return e.stack
+	}
+
+	var err error = e.cause
+	for err != nil {
+		if st, ok := err.(interface{ StackTrace() errors.StackTrace }); ok {
+			return st.StackTrace()
+		}
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		err = u.Unwrap()
+	}
+	return nil
+}
+
+func callers() errors.StackTrace {
+	err, ok := errors.New("").(interface{ StackTrace() errors.StackTrace })
+	if !ok {
+		return nil
+	}
+	return err.StackTrace()
+}
+
+// Unwrap returns the cause wrapped by err, or nil if err wasn't created via
+// New/Wrap or has no cause.
+func Unwrap(err error) error {
+	kv, ok := err.(*kvError)
+	if !ok {
+		return nil
+	}
+	return kv.cause
+}
+
+// Ctx flattens the key-value context of every layer in err's Wrap chain
+// into a single map. Where the same key appears at more than one layer, the
+// outermost (most recently wrapped) value wins.
+func Ctx(err error) map[string]interface{} {
+	ctx := make(map[string]interface{})
+	for {
+		kv, ok := err.(*kvError)
+		if !ok {
+			break
+		}
+		for i := 0; i+1 < len(kv.kvs); i += 2 {
+			key, ok := kv.kvs[i].(string)
+			if !ok {
+				continue
+			}
+			if _, exists := ctx[key]; !exists {
+				ctx[key] = kv.kvs[i+1]
+			}
+		}
+		err = kv.cause
+	}
+	return ctx
+}
+
+// Layer is one message+context step of a Wrap chain, in the shape logged
+// under the "error.causes" field.
+type Layer struct {
+	Msg string                 `json:"msg"`
+	Kvs map[string]interface{} `json:"kvs,omitempty"`
+}
+
+// Causes flattens err's Wrap chain into a slice of Layer, outermost first.
+// It returns nil when err wasn't created via New/Wrap.
+func Causes(err error) []Layer {
+	var layers []Layer
+	for {
+		kv, ok := err.(*kvError)
+		if !ok {
+			break
+		}
+		layers = append(layers, Layer{Msg: kv.msg, Kvs: kvsToMap(kv.kvs)})
+		err = kv.cause
+	}
+	return layers
+}
+
+func kvsToMap(kvs []interface{}) map[string]interface{} {
+	if len(kvs) == 0 {
+		return nil
+	}
+
+	m := make(map[string]interface{}, len(kvs)/2)
+	for i := 0; i+1 < len(kvs); i += 2 {
+		key, ok := kvs[i].(string)
+		if !ok {
+			continue
+		}
+		m[key] = kvs[i+1]
+	}
+	return m
+}
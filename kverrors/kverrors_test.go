@@ -0,0 +1,101 @@
+package kverrors
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Causes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("flattens a Wrap chain outermost first", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		err := Wrap(Wrap(New("root", "a", 1), "mid", "b", 2), "outer", "c", 3)
+
+		// Act
+		layers := Causes(err)
+
+		// Assert
+		require.Len(t, layers, 3)
+		assert.Equal(t, "outer", layers[0].Msg)
+		assert.Equal(t, 3, layers[0].Kvs["c"])
+		assert.Equal(t, "mid", layers[1].Msg)
+		assert.Equal(t, 2, layers[1].Kvs["b"])
+		assert.Equal(t, "root", layers[2].Msg)
+		assert.Equal(t, 1, layers[2].Kvs["a"])
+	})
+
+	t.Run("returns nil for an error not created via New/Wrap", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, Causes(errors.New("plain")))
+	})
+}
+
+func Test_Ctx(t *testing.T) {
+	t.Parallel()
+
+	t.Run("flattens kvs across layers, outermost wins on key conflict", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		err := Wrap(New("root", "key", "root-value", "only-root", true), "outer", "key", "outer-value")
+
+		// Act
+		ctx := Ctx(err)
+
+		// Assert
+		assert.Equal(t, "outer-value", ctx["key"])
+		assert.Equal(t, true, ctx["only-root"])
+	})
+
+	t.Run("returns an empty map for an error not created via New/Wrap", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, Ctx(errors.New("plain")))
+	})
+}
+
+func Test_StackTrace(t *testing.T) {
+	t.Parallel()
+
+	t.Run("New captures a stack trace preserved through later Wrap calls", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		err := Wrap(New("root"), "outer")
+
+		// Act
+		st, ok := err.(interface{ StackTrace() errors.StackTrace })
+
+		// Assert
+		require.True(t, ok)
+		assert.NotEmpty(t, st.StackTrace())
+	})
+}
+
+func Test_Unwrap(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns the wrapped cause", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		root := New("root")
+		err := Wrap(root, "outer")
+
+		// Act / Assert
+		assert.Equal(t, root, Unwrap(err))
+	})
+
+	t.Run("returns nil when err has no cause", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, Unwrap(New("root")))
+	})
+}
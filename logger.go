@@ -4,12 +4,23 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"runtime"
 	"sync/atomic"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/pkgerrors"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/VladimirYalumov/logger/kverrors"
 )
 
+// defaultCallerSkip matches the stack depth of the former hardcoded
+// event.Caller(3) call: it skips withFieldsAndCaller, the Logger method and
+// the package-level Debug/Info/... func, landing on the user's call site.
+// zerolog's event.Caller adds its own internal frames on top of this.
+const defaultCallerSkip = 3
+
 type Level int8
 
 const (
@@ -25,17 +36,55 @@ const (
 )
 
 var (
-	stacktraceEnabled atomic.Bool
-	callerEnabled     atomic.Bool
+	stacktraceEnabled   atomic.Bool
+	callerEnabled       atomic.Bool
+	traceContextEnabled atomic.Bool
+	callerSkip          atomic.Int32
 )
 
 type Logger struct {
 	l *zerolog.Logger
+
+	// sink is the Sink the logger currently writes to, set by WithSink or
+	// WithAsync. It is nil when the logger writes directly to os.Stdout
+	// through zerolog's default writer.
+	sink Sink
+
+	hooks             []Hook
+	sampler           Sampler
+	filters           []fieldFilter
+	redactionDisabled bool
+
+	// callerPrettyfier, when set, overrides how the caller/func fields are
+	// rendered; see WithCallerPrettyfier.
+	callerPrettyfier func(frame runtime.Frame) (function, file string)
+	reportFunction   bool
+}
+
+type fieldFilter struct {
+	key   string
+	allow func(v interface{}) bool
 }
 
 func init() {
 	zerolog.ErrorStackMarshaler = pkgerrors.MarshalStack
+	zerolog.ErrorMarshalFunc = marshalError
 	zerolog.DefaultContextLogger = New(ErrorLevel).l
+	callerSkip.Store(defaultCallerSkip)
+}
+
+// marshalError renders a kverrors Wrap chain as a nested "causes" object
+// instead of flattening it to err.Error(), so each layer's key-value
+// context survives into the structured log record.
+func marshalError(err error) interface{} {
+	causes := kverrors.Causes(err)
+	if len(causes) == 0 {
+		return err
+	}
+
+	return struct {
+		Causes []kverrors.Layer `json:"causes"`
+	}{Causes: causes}
 }
 
 func New(level Level, opts ...Option) *Logger {
@@ -63,35 +112,137 @@ func SetCallerEnabled(enabled bool) {
 	callerEnabled.Store(enabled)
 }
 
+func SetTraceContextEnabled(enabled bool) {
+	traceContextEnabled.Store(enabled)
+}
+
+// SetCallerSkip sets how many stack frames Caller reporting skips before
+// recording file:line/func, so a package that wraps Logger in its own
+// helper functions can account for the extra frames it adds.
+func SetCallerSkip(n int) {
+	callerSkip.Store(int32(n))
+}
+
 func (l *Logger) Level(level Level) *Logger {
 	zl := l.l.Level(zerolog.Level(level))
-	return &Logger{l: &zl}
+	return l.clone(&zl)
 }
 
 func (l *Logger) With(kvs ...interface{}) *Logger {
 	zl := l.l.With().Fields(kvs).Logger()
-	return &Logger{l: &zl}
+	return l.clone(&zl)
+}
+
+// FilterKey drops events whose kvs contain key when allow(value) returns
+// false, at every level from Debug to Error; Fatal and Panic events are
+// never filtered. It returns a new Logger, leaving l unmodified.
+func (l *Logger) FilterKey(key string, allow func(v interface{}) bool) *Logger {
+	clone := l.clone(l.l)
+	clone.filters = append(append([]fieldFilter(nil), l.filters...), fieldFilter{key: key, allow: allow})
+	return clone
+}
+
+// clone returns a copy of l backed by zl, preserving its sink, hooks,
+// sampler and filters.
+func (l *Logger) clone(zl *zerolog.Logger) *Logger {
+	return &Logger{
+		l:                 zl,
+		sink:              l.sink,
+		hooks:             l.hooks,
+		sampler:           l.sampler,
+		filters:           l.filters,
+		redactionDisabled: l.redactionDisabled,
+		callerPrettyfier:  l.callerPrettyfier,
+		reportFunction:    l.reportFunction,
+	}
+}
+
+// redactKVs applies every registered key/value redactor to kvs, unless
+// redaction has been disabled for this logger via WithRedaction.
+func (l *Logger) redactKVs(kvs []interface{}) []interface{} {
+	if l.redactionDisabled || len(kvs) == 0 {
+		return kvs
+	}
+
+	out := make([]interface{}, len(kvs))
+	copy(out, kvs)
+	for i := 0; i+1 < len(out); i += 2 {
+		key, _ := out[i].(string)
+		out[i+1] = redactField(key, out[i+1])
+	}
+	return out
+}
+
+// shouldLog reports whether an event at level with kvs should be logged,
+// consulting the logger's Sampler and FilterKey predicates.
+func (l *Logger) shouldLog(level Level, kvs []interface{}) bool {
+	if l.sampler != nil && !l.sampler.Sample(level) {
+		return false
+	}
+
+	for i := 0; i+1 < len(kvs); i += 2 {
+		key, ok := kvs[i].(string)
+		if !ok {
+			continue
+		}
+		for _, f := range l.filters {
+			if f.key == key && !f.allow(kvs[i+1]) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (l *Logger) runHooks(level Level, msg string, kvs []interface{}) {
+	for _, h := range l.hooks {
+		h.Run(level, msg, kvs)
+	}
 }
 
 func (l *Logger) Debug(msg string, kvs ...interface{}) {
+	if !l.shouldLog(DebugLevel, kvs) {
+		return
+	}
+	kvs = l.redactKVs(kvs)
+
 	event := l.l.Debug()
-	event = withFieldsAndCaller(event, kvs...)
+	event = l.withFieldsAndCaller(event, kvs...)
+	l.runHooks(DebugLevel, msg, kvs)
 	event.Msg(msg)
 }
 
 func (l *Logger) Info(msg string, kvs ...interface{}) {
+	if !l.shouldLog(InfoLevel, kvs) {
+		return
+	}
+	kvs = l.redactKVs(kvs)
+
 	event := l.l.Info()
-	event = withFieldsAndCaller(event, kvs...)
+	event = l.withFieldsAndCaller(event, kvs...)
+	l.runHooks(InfoLevel, msg, kvs)
 	event.Msg(msg)
 }
 
 func (l *Logger) Warn(msg string, kvs ...interface{}) {
+	if !l.shouldLog(WarnLevel, kvs) {
+		return
+	}
+	kvs = l.redactKVs(kvs)
+
 	event := l.l.Warn()
-	event = withFieldsAndCaller(event, kvs...)
+	event = l.withFieldsAndCaller(event, kvs...)
+	l.runHooks(WarnLevel, msg, kvs)
 	event.Msg(msg)
 }
 
 func (l *Logger) Error(err error, msg string, kvs ...interface{}) {
+	if !l.shouldLog(ErrorLevel, kvs) {
+		return
+	}
+	kvs = l.redactKVs(kvs)
+
 	event := l.l.Error()
 
 	if stacktraceEnabled.Load() {
@@ -99,44 +250,67 @@ func (l *Logger) Error(err error, msg string, kvs ...interface{}) {
 	}
 
 	event = event.Err(err)
-	event = withFieldsAndCaller(event, kvs...)
+	event = l.withFieldsAndCaller(event, kvs...)
+	l.runHooks(ErrorLevel, msg, kvs)
 	event.Msg(msg)
 }
 
+// RecordError mirrors Error, additionally recording err on the span active
+// in ctx (if any) via span.RecordError and marking the span as errored, so a
+// single call both logs the error and annotates the active trace.
+func (l *Logger) RecordError(ctx context.Context, err error, msg string, kvs ...interface{}) {
+	if span := trace.SpanFromContext(ctx); span.IsRecording() {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, msg)
+	}
+
+	l.Error(err, msg, kvs...)
+}
+
 func (l *Logger) Fatal(msg string, kvs ...interface{}) {
+	kvs = l.redactKVs(kvs)
 	event := l.l.Fatal()
-	event = withFieldsAndCaller(event, kvs...)
-	event.Msg(msg)
+	event = l.withFieldsAndCaller(event, kvs...)
+	l.runHooks(FatalLevel, msg, kvs)
+	event.Msg(msg) // exits the process; nothing below this line runs
 }
 
 func (l *Logger) Panic(msg string, kvs ...interface{}) {
+	kvs = l.redactKVs(kvs)
 	event := l.l.Panic()
-	event = withFieldsAndCaller(event, kvs...)
-	event.Msg(msg)
+	event = l.withFieldsAndCaller(event, kvs...)
+	l.runHooks(PanicLevel, msg, kvs)
+	event.Msg(msg) // panics; nothing below this line runs
 }
 
 func Debug(ctx context.Context, msg string, kvs ...interface{}) {
-	FromContext(ctx).Debug(msg, kvs...)
+	loggerFromContext(ctx).Debug(msg, kvs...)
 }
 
 func Info(ctx context.Context, msg string, kvs ...interface{}) {
-	FromContext(ctx).Info(msg, kvs...)
+	loggerFromContext(ctx).Info(msg, kvs...)
 }
 
 func Warn(ctx context.Context, msg string, kvs ...interface{}) {
-	FromContext(ctx).Warn(msg, kvs...)
+	loggerFromContext(ctx).Warn(msg, kvs...)
 }
 
 func Error(ctx context.Context, err error, msg string, kvs ...interface{}) {
-	FromContext(ctx).Error(err, msg, kvs...)
+	loggerFromContext(ctx).Error(err, msg, kvs...)
 }
 
 func Fatal(ctx context.Context, msg string, kvs ...interface{}) {
-	FromContext(ctx).Fatal(msg, kvs...)
+	loggerFromContext(ctx).Fatal(msg, kvs...)
 }
 
 func Panic(ctx context.Context, msg string, kvs ...interface{}) {
-	FromContext(ctx).Panic(msg, kvs...)
+	loggerFromContext(ctx).Panic(msg, kvs...)
+}
+
+// RecordError logs err via Error and, when ctx carries an active span,
+// records err on that span and marks it as errored.
+func RecordError(ctx context.Context, err error, msg string, kvs ...interface{}) {
+	loggerFromContext(ctx).RecordError(ctx, err, msg, kvs...)
 }
 
 func ParseLevel(levelStr string) (Level, error) {
@@ -159,13 +333,50 @@ func ParseLevel(levelStr string) (Level, error) {
 	return level, nil
 }
 
-func withFieldsAndCaller(event *zerolog.Event, kvs ...interface{}) *zerolog.Event {
+func (l *Logger) withFieldsAndCaller(event *zerolog.Event, kvs ...interface{}) *zerolog.Event {
 	if len(kvs) > 0 {
 		event = event.Fields(kvs)
 	}
 
-	if callerEnabled.Load() {
-		event = event.Caller(3)
+	if !callerEnabled.Load() {
+		return event
+	}
+
+	skip := int(callerSkip.Load())
+
+	if l.callerPrettyfier == nil && !l.reportFunction {
+		return event.Caller(skip)
+	}
+
+	// skip must match zerolog's own event.Caller(skip) above so both
+	// branches resolve the same frame regardless of whether a
+	// CallerPrettyfier/func field is configured.
+	pc, file, line, ok := runtime.Caller(skip)
+	if !ok {
+		return event
+	}
+
+	frames := runtime.CallersFrames([]uintptr{pc})
+	frame, _ := frames.Next()
+	frame.File, frame.Line = file, line
+
+	callerStr := fmt.Sprintf("%s:%d", frame.File, frame.Line)
+	funcName := frame.Function
+
+	if l.callerPrettyfier != nil {
+		if function, prettyFile := l.callerPrettyfier(frame); prettyFile != "" || function != "" {
+			if prettyFile != "" {
+				callerStr = prettyFile
+			}
+			if function != "" {
+				funcName = function
+			}
+		}
+	}
+
+	event = event.Str(zerolog.CallerFieldName, callerStr)
+	if l.reportFunction {
+		event = event.Str("func", funcName)
 	}
 
 	return event
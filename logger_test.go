@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -387,3 +388,28 @@ func Test_Caller(t *testing.T) {
 		assert.Equal(t, log.Caller, ``)
 	})
 }
+
+func Test_Caller_PrettyfierMatchesDefaultFrame(t *testing.T) {
+	// Not parallel: toggles the global caller-enabled switch.
+	SetCallerEnabled(true)
+	defer SetCallerEnabled(false)
+
+	capture := func(opts ...Option) string {
+		buf := new(bytes.Buffer)
+		l := New(DebugLevel, append([]Option{testJsonEnable(buf)}, opts...)...)
+		l.Info("msg")
+		return newTestLogType(buf.Bytes()).Caller
+	}
+
+	// Act: both calls below resolve through the same call site, so the
+	// default (event.Caller) and prettyfier (runtime.Caller) branches must
+	// land on the identical frame, not one off from each other.
+	prettyfier := WithCallerPrettyfier(func(frame runtime.Frame) (string, string) {
+		return "", fmt.Sprintf("%s:%d", frame.File, frame.Line)
+	})
+	defaultCaller, prettyCaller := capture(), capture(prettyfier)
+
+	// Assert
+	assert.Contains(t, defaultCaller, "/logger_test.go:")
+	assert.Equal(t, defaultCaller, prettyCaller)
+}
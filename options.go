@@ -1,8 +1,11 @@
 package logger
 
 import (
-	"github.com/rs/zerolog"
 	"os"
+	"runtime"
+	"time"
+
+	"github.com/rs/zerolog"
 )
 
 type Option func(*Logger)
@@ -25,3 +28,99 @@ func test(l *Logger) {
 	zl := l.l.Output(zerolog.ConsoleWriter{Out: os.Stdout}).With().Logger()
 	l.l = &zl
 }
+
+// WithSink routes log events through s instead of the default os.Stdout
+// writer. s is also registered with Shutdown, so a deferred Shutdown call
+// flushes and closes it on process exit.
+func WithSink(s Sink) Option {
+	return func(l *Logger) {
+		l.sink = s
+		registerSink(s)
+
+		zl := l.l.Output(&sinkWriter{sink: s}).With().Logger()
+		l.l = &zl
+	}
+}
+
+// WithAsync makes the logger's current sink (or os.Stdout, if WithSink
+// wasn't used) asynchronous: events are buffered on a bounded, drop-oldest
+// ring of bufSize entries and written by a background goroutine, which also
+// flushes every flushInterval. Combine with Shutdown to avoid losing
+// buffered events on exit.
+func WithAsync(bufSize int, flushInterval time.Duration) Option {
+	return func(l *Logger) {
+		underlying := l.sink
+		if underlying == nil {
+			underlying = NewStdoutSink()
+		} else {
+			// underlying was already registered by WithSink; once async wraps
+			// it, async is what Shutdown should flush/close, not both.
+			unregisterSink(underlying)
+		}
+
+		async := newAsyncSink(underlying, bufSize, flushInterval)
+		l.sink = async
+		registerSink(async)
+
+		zl := l.l.Output(&sinkWriter{sink: async}).With().Logger()
+		l.l = &zl
+	}
+}
+
+// WithHook registers h to run for every event the logger emits, after
+// sampling and filtering but before the event reaches the sink.
+func WithHook(h Hook) Option {
+	return func(l *Logger) {
+		l.hooks = append(l.hooks, h)
+	}
+}
+
+// WithSampler attaches s to the logger, so every Debug, Info, Warn and Error
+// event is first passed through s.Sample before being logged. Fatal and
+// Panic events bypass the sampler.
+func WithSampler(s Sampler) Option {
+	return func(l *Logger) {
+		l.sampler = s
+	}
+}
+
+// WithRedaction configures redaction for the logger. By default every
+// logger redacts fields matched by a registered RegisterRedactor/
+// RegisterValueRedactor rule; use RedactionConfig.Disabled to turn that off,
+// e.g. in tests that assert on raw field values.
+func WithRedaction(cfg RedactionConfig) Option {
+	return func(l *Logger) {
+		l.redactionDisabled = cfg.Disabled
+		if cfg.Salt != "" {
+			redactionSalt.Store(cfg.Salt)
+		}
+	}
+}
+
+// WithCallerPrettyfier overrides how the logger renders its caller/func
+// fields: fn receives the resolved runtime.Frame and returns the function
+// and file (typically "file:line") strings to log, following the logrus
+// CallerPrettyfier convention. Returning an empty string for either leaves
+// that field at its default rendering.
+func WithCallerPrettyfier(fn func(frame runtime.Frame) (function, file string)) Option {
+	return func(l *Logger) {
+		l.callerPrettyfier = fn
+	}
+}
+
+// WithReportFunction enables a "func" field alongside "caller", set to the
+// calling function's name as resolved via runtime.CallersFrames.
+func WithReportFunction(enabled bool) Option {
+	return func(l *Logger) {
+		l.reportFunction = enabled
+	}
+}
+
+// WithTraceContext enables automatic trace_id/span_id/trace_flags injection
+// for the package-level Debug/Info/Warn/Error/Fatal/Panic funcs, sourced
+// from the active OpenTelemetry span in the logging context.
+func WithTraceContext() Option {
+	return func(l *Logger) {
+		traceContextEnabled.Store(true)
+	}
+}
@@ -0,0 +1,285 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"reflect"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	redactedPlaceholder = "***"
+	maxRedactDepth      = 6
+)
+
+// RedactionConfig configures how RegisterRedactor/RegisterValueRedactor
+// rules are applied by a Logger.
+type RedactionConfig struct {
+	// Disabled turns redaction off for this logger entirely, e.g. in tests
+	// that assert on raw field values.
+	Disabled bool
+
+	// Salt, when non-empty, switches every built-in redactor's replacement
+	// from the fixed string "***" to a salted SHA-256 prefix, so operators
+	// can still correlate occurrences of the same secret across log lines
+	// without the raw value being recoverable. Applies process-wide.
+	Salt string
+}
+
+var redactionSalt atomic.Value // string
+
+func maskValue(v interface{}) interface{} {
+	salt, _ := redactionSalt.Load().(string)
+	if salt == "" {
+		return redactedPlaceholder
+	}
+
+	sum := sha256.Sum256([]byte(salt + fmt.Sprint(v)))
+	return "sha256:" + hex.EncodeToString(sum[:])[:12]
+}
+
+type keyRedactor struct {
+	re *regexp.Regexp
+	fn func(v interface{}) interface{}
+}
+
+var (
+	redactorsMu    sync.RWMutex
+	keyRedactors   []keyRedactor
+	valueRedactors []func(v interface{}) interface{}
+)
+
+// RegisterRedactor registers fn to run on the value of any field whose key
+// matches the case-insensitive regular expression keyPattern.
+func RegisterRedactor(keyPattern string, fn func(v interface{}) interface{}) error {
+	re, err := regexp.Compile("(?i)" + keyPattern)
+	if err != nil {
+		return fmt.Errorf("compile redaction key pattern %q: %w", keyPattern, err)
+	}
+
+	redactorsMu.Lock()
+	defer redactorsMu.Unlock()
+	keyRedactors = append(keyRedactors, keyRedactor{re: re, fn: fn})
+
+	return nil
+}
+
+// RegisterValueRedactor registers fn to run on every field value regardless
+// of key. fn should return v unchanged when it doesn't recognize the
+// value's shape, and the replacement otherwise.
+func RegisterValueRedactor(fn func(v interface{}) interface{}) {
+	redactorsMu.Lock()
+	defer redactorsMu.Unlock()
+	valueRedactors = append(valueRedactors, fn)
+}
+
+func init() {
+	for _, key := range []string{"password", "authorization", "api_key", "apikey", "token", "set-cookie", "set_cookie"} {
+		_ = RegisterRedactor(regexp.QuoteMeta(key), func(v interface{}) interface{} {
+			return maskValue(v)
+		})
+	}
+
+	RegisterValueRedactor(redactJWT)
+	RegisterValueRedactor(redactCreditCard)
+	RegisterValueRedactor(redactEmail)
+	RegisterValueRedactor(redactIP)
+}
+
+var jwtRE = regexp.MustCompile(`^eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*$`)
+
+func redactJWT(v interface{}) interface{} {
+	s, ok := v.(string)
+	if !ok || !jwtRE.MatchString(s) {
+		return v
+	}
+	return maskValue(v)
+}
+
+var emailRE = regexp.MustCompile(`^[a-zA-Z0-9.!#$%&'*+/=?^_` + "`" + `{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)+$`)
+
+func redactEmail(v interface{}) interface{} {
+	s, ok := v.(string)
+	if !ok || !emailRE.MatchString(s) {
+		return v
+	}
+	return maskValue(v)
+}
+
+func redactIP(v interface{}) interface{} {
+	s, ok := v.(string)
+	if !ok || net.ParseIP(s) == nil {
+		return v
+	}
+	return maskValue(v)
+}
+
+var creditCardRE = regexp.MustCompile(`^[0-9][0-9 -]{11,22}[0-9]$`)
+
+func redactCreditCard(v interface{}) interface{} {
+	s, ok := v.(string)
+	if !ok || !creditCardRE.MatchString(s) || !luhnValid(s) {
+		return v
+	}
+	return maskValue(v)
+}
+
+func luhnValid(s string) bool {
+	sum, digits := 0, 0
+	alternate := false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		if c < '0' || c > '9' {
+			return false
+		}
+
+		d := int(c - '0')
+		digits++
+		if alternate {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		alternate = !alternate
+	}
+
+	return digits >= 12 && sum%10 == 0
+}
+
+// redactField applies the first key redactor matching key to v or, failing
+// that, the first value redactor that changes v, recursing into maps and
+// structs when nothing at this level matched.
+func redactField(key string, v interface{}) interface{} {
+	return redactValue(key, v, 0)
+}
+
+func redactValue(key string, v interface{}, depth int) interface{} {
+	if v == nil || depth >= maxRedactDepth {
+		return v
+	}
+
+	redactorsMu.RLock()
+	for _, kr := range keyRedactors {
+		if kr.re.MatchString(key) {
+			redactorsMu.RUnlock()
+			return kr.fn(v)
+		}
+	}
+	for _, vr := range valueRedactors {
+		if redacted := vr(v); !reflect.DeepEqual(redacted, v) {
+			redactorsMu.RUnlock()
+			return redacted
+		}
+	}
+	redactorsMu.RUnlock()
+
+	return redactNested(v, depth)
+}
+
+// hasCustomEncoding reports whether v controls its own wire representation
+// (time.Time, or a json.Marshaler/encoding.TextMarshaler implementation).
+// Reflecting into such a value's fields would bypass that encoding and,
+// for types like time.Time whose fields are all unexported, serialize as
+// an empty object — so redactNested leaves it alone instead.
+func hasCustomEncoding(v interface{}) bool {
+	switch v.(type) {
+	case time.Time:
+		return true
+	}
+	if _, ok := v.(json.Marshaler); ok {
+		return true
+	}
+	if _, ok := v.(encoding.TextMarshaler); ok {
+		return true
+	}
+	return false
+}
+
+// redactNested walks into maps/structs/pointers looking for fields that
+// match a registered redactor, since secrets are often buried in a nested
+// payload rather than passed as a top-level kv pair. It returns v itself,
+// unchanged, whenever nothing underneath it was actually redacted, so
+// values with significant field order or custom marshaling aren't mangled
+// into a plain map for no reason.
+func redactNested(v interface{}, depth int) interface{} {
+	if hasCustomEncoding(v) {
+		return v
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Map:
+		return redactMap(rv, depth)
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return v
+		}
+		elem := rv.Elem().Interface()
+		redacted := redactNested(elem, depth)
+		if reflect.DeepEqual(redacted, elem) {
+			return v
+		}
+		return redacted
+	case reflect.Struct:
+		return redactStruct(rv, depth)
+	default:
+		return v
+	}
+}
+
+func redactMap(rv reflect.Value, depth int) interface{} {
+	changed := false
+	out := make(map[string]interface{}, rv.Len())
+	for _, k := range rv.MapKeys() {
+		orig := rv.MapIndex(k).Interface()
+		keyStr := fmt.Sprint(k.Interface())
+
+		redacted := redactValue(keyStr, orig, depth+1)
+		out[keyStr] = redacted
+		if !reflect.DeepEqual(redacted, orig) {
+			changed = true
+		}
+	}
+
+	if !changed {
+		return rv.Interface()
+	}
+	return out
+}
+
+func redactStruct(rv reflect.Value, depth int) interface{} {
+	t := rv.Type()
+	changed := false
+	out := make(map[string]interface{}, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" { // unexported
+			continue
+		}
+
+		orig := rv.Field(i).Interface()
+		redacted := redactValue(f.Name, orig, depth+1)
+		out[f.Name] = redacted
+		if !reflect.DeepEqual(redacted, orig) {
+			changed = true
+		}
+	}
+
+	if !changed {
+		return rv.Interface()
+	}
+	return out
+}
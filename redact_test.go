@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type redactTestPayload struct {
+	Username string
+	Password string
+}
+
+// stringerPayload implements fmt.Stringer but not json.Marshaler, so it is
+// not one of redactNested's short-circuited types; its exported field is
+// still reachable and redactable via reflection.
+type stringerPayload struct {
+	Token string
+}
+
+func (p stringerPayload) String() string { return "stringerPayload{}" }
+
+func Test_RedactField_CustomEncoding(t *testing.T) {
+	t.Parallel()
+
+	t.Run("time.Time passes through unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		// Act
+		redacted := redactField("created_at", now)
+
+		// Assert: reflecting into time.Time's unexported fields would have
+		// serialized it as "{}", losing the timestamp entirely.
+		require.IsType(t, time.Time{}, redacted)
+		assert.True(t, now.Equal(redacted.(time.Time)))
+		assert.Equal(t, now, redacted)
+	})
+
+	t.Run("json.Marshaler value passes through unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		v := jsonMarshalerStub{N: 7}
+
+		// Act
+		redacted := redactField("payload", v)
+
+		// Assert
+		assert.Equal(t, jsonMarshalerStub{N: 7}, redacted)
+	})
+}
+
+// jsonMarshalerStub implements json.Marshaler so redactNested must leave it
+// alone rather than reflecting into its field.
+type jsonMarshalerStub struct{ N int }
+
+func (m jsonMarshalerStub) MarshalJSON() ([]byte, error) {
+	return []byte(`"stub"`), nil
+}
+
+func Test_RedactField_Nested(t *testing.T) {
+	t.Parallel()
+
+	t.Run("redacts a matching field nested in a struct", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		v := redactTestPayload{Username: "alice", Password: "hunter2"}
+
+		// Act
+		redacted := redactField("user", v)
+
+		// Assert
+		m, ok := redacted.(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "alice", m["Username"])
+		assert.Equal(t, redactedPlaceholder, m["Password"])
+	})
+
+	t.Run("redacts a matching field nested in a map", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		v := map[string]interface{}{"username": "alice", "token": "abc123"}
+
+		// Act
+		redacted := redactField("user", v)
+
+		// Assert
+		m, ok := redacted.(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "alice", m["username"])
+		assert.Equal(t, redactedPlaceholder, m["token"])
+	})
+
+	t.Run("returns the original value unchanged when nothing inside matched", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		v := struct{ Username string }{Username: "alice"}
+
+		// Act
+		redacted := redactField("user", v)
+
+		// Assert: a struct with nothing to redact keeps its original type
+		// rather than being rebuilt into a map[string]interface{}.
+		assert.Equal(t, v, redacted)
+	})
+
+	t.Run("redacts a field reachable through a Stringer struct", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		v := stringerPayload{Token: "hunter2"}
+
+		// Act
+		redacted := redactField("payload", v)
+
+		// Assert: Stringer isn't a short-circuited encoding, so the struct is
+		// still walked and its matching field redacted.
+		m, ok := redacted.(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, redactedPlaceholder, m["Token"])
+	})
+}
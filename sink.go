@@ -0,0 +1,457 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Sink is a destination for log events. Implementations must be safe for
+// concurrent use, since a Logger may be shared across goroutines.
+type Sink interface {
+	// Write emits event, the already-encoded log record, for level.
+	Write(level Level, event []byte) error
+
+	// Flush blocks until any buffered events have been handed to the
+	// underlying destination.
+	Flush() error
+
+	// Close flushes and releases any resources held by the sink. A closed
+	// sink must not be written to again.
+	Close() error
+}
+
+var (
+	registeredSinksMu sync.Mutex
+	registeredSinks   []Sink
+)
+
+func registerSink(s Sink) {
+	registeredSinksMu.Lock()
+	defer registeredSinksMu.Unlock()
+	registeredSinks = append(registeredSinks, s)
+}
+
+// unregisterSink removes s from the Shutdown registry. It's used when a
+// sink gets wrapped by another (e.g. WithAsync wrapping a sink set by
+// WithSink), so Shutdown flushes/closes the outermost wrapper exactly once
+// instead of also reaching the sink it wraps.
+func unregisterSink(s Sink) {
+	registeredSinksMu.Lock()
+	defer registeredSinksMu.Unlock()
+	for i, existing := range registeredSinks {
+		if existing == s {
+			registeredSinks = append(registeredSinks[:i], registeredSinks[i+1:]...)
+			return
+		}
+	}
+}
+
+// Shutdown flushes and closes every Sink registered via WithSink or
+// WithAsync, stopping as soon as ctx is done. It is typically deferred in
+// main so buffered/async sinks don't lose events on process exit.
+func Shutdown(ctx context.Context) error {
+	registeredSinksMu.Lock()
+	sinks := append([]Sink(nil), registeredSinks...)
+	registeredSinksMu.Unlock()
+
+	var errs []error
+	for _, s := range sinks {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := s.Flush(); err != nil {
+			errs = append(errs, fmt.Errorf("flush sink: %w", err))
+		}
+		if err := s.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close sink: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// sinkWriter adapts a Sink to zerolog's io.Writer/LevelWriter output, so it
+// can be installed via zerolog.Logger.Output.
+type sinkWriter struct {
+	sink Sink
+}
+
+func (w *sinkWriter) Write(p []byte) (int, error) {
+	return len(p), w.sink.Write(InfoLevel, p)
+}
+
+func (w *sinkWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	return len(p), w.sink.Write(Level(level), p)
+}
+
+// writerSink adapts a plain io.Writer (e.g. os.Stdout) into a Sink.
+type writerSink struct {
+	w io.Writer
+}
+
+// NewStdoutSink returns a Sink that writes every event to os.Stdout.
+func NewStdoutSink() Sink {
+	return &writerSink{w: os.Stdout}
+}
+
+// NewStderrSink returns a Sink that writes every event to os.Stderr.
+func NewStderrSink() Sink {
+	return &writerSink{w: os.Stderr}
+}
+
+func (s *writerSink) Write(_ Level, event []byte) error {
+	_, err := s.w.Write(event)
+	return err
+}
+
+func (s *writerSink) Flush() error { return nil }
+func (s *writerSink) Close() error { return nil }
+
+// fileSink writes events to a file on disk, rotating it once it grows past
+// maxSizeBytes or gets older than maxAge.
+type fileSink struct {
+	mu       sync.Mutex
+	path     string
+	maxSize  int64
+	maxAge   time.Duration
+	f        *os.File
+	size     int64
+	openedAt time.Time
+}
+
+// NewFileSink opens (creating if necessary) the file at path for appending,
+// rotating it to path.<timestamp> once it exceeds maxSizeBytes or maxAge.
+// A zero maxSizeBytes or maxAge disables that rotation trigger.
+func NewFileSink(path string, maxSizeBytes int64, maxAge time.Duration) (Sink, error) {
+	f, info, err := openLogFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileSink{
+		path:     path,
+		maxSize:  maxSizeBytes,
+		maxAge:   maxAge,
+		f:        f,
+		size:     info.Size(),
+		openedAt: info.ModTime(),
+	}, nil
+}
+
+func openLogFile(path string) (*os.File, os.FileInfo, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open log file %q: %w", path, err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, nil, fmt.Errorf("stat log file %q: %w", path, err)
+	}
+
+	return f, info, nil
+}
+
+func (s *fileSink) Write(_ Level, event []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.shouldRotateLocked() {
+		if err := s.rotateLocked(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.f.Write(event)
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("write log file %q: %w", s.path, err)
+	}
+
+	return nil
+}
+
+func (s *fileSink) shouldRotateLocked() bool {
+	if s.maxSize > 0 && s.size >= s.maxSize {
+		return true
+	}
+	if s.maxAge > 0 && time.Since(s.openedAt) >= s.maxAge {
+		return true
+	}
+	return false
+}
+
+func (s *fileSink) rotateLocked() error {
+	if err := s.f.Close(); err != nil {
+		return fmt.Errorf("close log file %q: %w", s.path, err)
+	}
+
+	rotated := fmt.Sprintf("%s.%s", s.path, time.Now().Format("20060102T150405.000"))
+	if err := os.Rename(s.path, rotated); err != nil {
+		return fmt.Errorf("rotate log file %q: %w", s.path, err)
+	}
+
+	f, info, err := openLogFile(s.path)
+	if err != nil {
+		return err
+	}
+
+	s.f = f
+	s.size = info.Size()
+	s.openedAt = info.ModTime()
+
+	return nil
+}
+
+func (s *fileSink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.f.Sync(); err != nil {
+		return fmt.Errorf("sync log file %q: %w", s.path, err)
+	}
+	return nil
+}
+
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.f.Close(); err != nil {
+		return fmt.Errorf("close log file %q: %w", s.path, err)
+	}
+	return nil
+}
+
+// MultiSink fans out events to a set of sinks, per level. Sinks added
+// without an explicit level receive every event regardless of level.
+type MultiSink struct {
+	mu      sync.Mutex
+	byLevel map[Level][]Sink
+	always  []Sink
+}
+
+// NewMultiSink returns an empty MultiSink; use Add to register sinks.
+func NewMultiSink() *MultiSink {
+	return &MultiSink{byLevel: make(map[Level][]Sink)}
+}
+
+// Add registers s to receive events at the given levels, or every level if
+// none are given. It returns the MultiSink so calls can be chained.
+func (m *MultiSink) Add(s Sink, levels ...Level) *MultiSink {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(levels) == 0 {
+		m.always = append(m.always, s)
+		return m
+	}
+	for _, level := range levels {
+		m.byLevel[level] = append(m.byLevel[level], s)
+	}
+	return m
+}
+
+func (m *MultiSink) Write(level Level, event []byte) error {
+	m.mu.Lock()
+	sinks := append(append([]Sink(nil), m.always...), m.byLevel[level]...)
+	m.mu.Unlock()
+
+	var errs []error
+	for _, s := range sinks {
+		if err := s.Write(level, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *MultiSink) Flush() error {
+	var errs []error
+	for _, s := range m.all() {
+		if err := s.Flush(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *MultiSink) Close() error {
+	var errs []error
+	for _, s := range m.all() {
+		if err := s.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *MultiSink) all() []Sink {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	seen := make(map[Sink]struct{})
+	var sinks []Sink
+	for _, s := range m.always {
+		if _, ok := seen[s]; !ok {
+			seen[s] = struct{}{}
+			sinks = append(sinks, s)
+		}
+	}
+	for _, levelSinks := range m.byLevel {
+		for _, s := range levelSinks {
+			if _, ok := seen[s]; !ok {
+				seen[s] = struct{}{}
+				sinks = append(sinks, s)
+			}
+		}
+	}
+	return sinks
+}
+
+type asyncEntry struct {
+	level Level
+	event []byte
+}
+
+// asyncSink buffers events in a bounded ring buffer and hands them to an
+// underlying Sink from a background goroutine, so the calling goroutine
+// never blocks on I/O. Once the buffer is full, the oldest queued event is
+// dropped to make room for the new one.
+type asyncSink struct {
+	underlying Sink
+	buf        chan asyncEntry
+	flushReq   chan chan struct{}
+	done       chan struct{}
+	stopped    chan struct{}
+	closeOnce  sync.Once
+	dropped    atomic.Uint64
+}
+
+// newAsyncSink starts a background goroutine flushing into underlying every
+// flushInterval (if positive) and whenever an event arrives, buffering up to
+// bufSize events before dropping the oldest. underlying is only ever
+// touched from that goroutine, so Write/Flush/Close never race with it.
+func newAsyncSink(underlying Sink, bufSize int, flushInterval time.Duration) *asyncSink {
+	if bufSize <= 0 {
+		bufSize = 1
+	}
+
+	s := &asyncSink{
+		underlying: underlying,
+		buf:        make(chan asyncEntry, bufSize),
+		flushReq:   make(chan chan struct{}),
+		done:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+	go s.run(flushInterval)
+
+	return s
+}
+
+func (s *asyncSink) run(flushInterval time.Duration) {
+	defer close(s.stopped)
+
+	var tick <-chan time.Time
+	if flushInterval > 0 {
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case e := <-s.buf:
+			_ = s.underlying.Write(e.level, e.event)
+		case <-tick:
+			s.drain()
+			_ = s.underlying.Flush()
+		case ack := <-s.flushReq:
+			s.drain()
+			_ = s.underlying.Flush()
+			close(ack)
+		case <-s.done:
+			s.drain()
+			return
+		}
+	}
+}
+
+func (s *asyncSink) drain() {
+	for {
+		select {
+		case e := <-s.buf:
+			_ = s.underlying.Write(e.level, e.event)
+		default:
+			return
+		}
+	}
+}
+
+func (s *asyncSink) Write(level Level, event []byte) error {
+	// event is reused by zerolog after Write returns, so it must be copied
+	// before crossing into the background goroutine.
+	buf := make([]byte, len(event))
+	copy(buf, event)
+	entry := asyncEntry{level: level, event: buf}
+
+	select {
+	case s.buf <- entry:
+		return nil
+	default:
+	}
+
+	select {
+	case <-s.buf:
+		s.dropped.Add(1)
+	default:
+	}
+
+	select {
+	case s.buf <- entry:
+	default:
+		s.dropped.Add(1)
+	}
+
+	return nil
+}
+
+// Dropped reports how many events have been discarded because the buffer
+// was full.
+func (s *asyncSink) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Flush drains every event currently buffered into the underlying sink and
+// flushes it, blocking until that's done, satisfying the Sink.Flush
+// contract. It's a no-op once the sink has already been closed.
+func (s *asyncSink) Flush() error {
+	ack := make(chan struct{})
+	select {
+	case s.flushReq <- ack:
+		<-ack
+	case <-s.stopped:
+	}
+	return nil
+}
+
+// Close stops the background goroutine, waiting for it to drain any
+// buffered events into underlying before closing underlying itself, so a
+// concurrent drain can never write to (or close) it out from under Close.
+func (s *asyncSink) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+	<-s.stopped
+	return s.underlying.Close()
+}
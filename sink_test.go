@@ -0,0 +1,129 @@
+package logger
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink records every Write call it receives along with how many
+// times Flush/Close were called, so tests can assert on what an asyncSink
+// handed it and when.
+type recordingSink struct {
+	mu      sync.Mutex
+	events  [][]byte
+	flushes int
+	closes  int
+}
+
+func (s *recordingSink) Write(_ Level, event []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *recordingSink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flushes++
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closes++
+	return nil
+}
+
+func (s *recordingSink) snapshot() [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]byte(nil), s.events...)
+}
+
+func Test_AsyncSink_Flush(t *testing.T) {
+	t.Parallel()
+
+	t.Run("drains buffered events into the underlying sink before returning", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange: flushInterval of 0 disables the ticker, so no periodic
+		// flush runs; Flush alone must have handed both events to rec.
+		rec := &recordingSink{}
+		s := newAsyncSink(rec, 8, 0)
+		require.NoError(t, s.Write(InfoLevel, []byte("one")))
+		require.NoError(t, s.Write(InfoLevel, []byte("two")))
+
+		// Act
+		err := s.Flush()
+
+		// Assert
+		require.NoError(t, err)
+		assert.Len(t, rec.snapshot(), 2)
+	})
+}
+
+func Test_AsyncSink_Close(t *testing.T) {
+	t.Parallel()
+
+	t.Run("drains buffered events into the underlying sink before closing it", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange
+		rec := &recordingSink{}
+		s := newAsyncSink(rec, 8, 0)
+		require.NoError(t, s.Write(InfoLevel, []byte("one")))
+
+		// Act
+		err := s.Close()
+
+		// Assert
+		require.NoError(t, err)
+		assert.Len(t, rec.snapshot(), 1)
+		assert.Equal(t, 1, rec.closes)
+	})
+
+	t.Run("does not race the background goroutine's final drain", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange: enough events that the drain on Close has real work to do,
+		// exercised under -race to catch a concurrent Write/Close on rec.
+		rec := &recordingSink{}
+		s := newAsyncSink(rec, 64, 0)
+		for i := 0; i < 50; i++ {
+			require.NoError(t, s.Write(InfoLevel, []byte("event")))
+		}
+
+		// Act
+		err := s.Close()
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, rec.closes)
+	})
+}
+
+func Test_AsyncSink_Write_DropsOldestWhenFull(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: construct the asyncSink without starting its run goroutine, so
+	// nothing drains s.buf concurrently and the overflow behavior of Write
+	// itself can be observed deterministically.
+	rec := &recordingSink{}
+	s := &asyncSink{underlying: rec, buf: make(chan asyncEntry, 2)}
+
+	// Act: fill the 2-entry buffer, then push a 3rd that must drop the oldest.
+	require.NoError(t, s.Write(InfoLevel, []byte("first")))
+	require.NoError(t, s.Write(InfoLevel, []byte("second")))
+	require.NoError(t, s.Write(InfoLevel, []byte("third")))
+
+	// Assert
+	assert.Equal(t, uint64(1), s.Dropped())
+	require.Len(t, s.buf, 2)
+	assert.Equal(t, []byte("second"), (<-s.buf).event)
+	assert.Equal(t, []byte("third"), (<-s.buf).event)
+}